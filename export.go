@@ -1,62 +1,153 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
+
+	"github.com/AbhinavAchha/syncrepo/internal/pool"
 )
 
-// getExportData function gets the data to export to JSON
-func getExportData(dirs []string) (jsonData map[string]string) {
-	jsonData = make(map[string]string, len(dirs))
-	wg := sync.WaitGroup{}
-	wg.Add(len(dirs))
-	mtx := sync.Mutex{}
+// getExportData function gathers an ExportRepo for every dir in dirs.
+func getExportData(ctx context.Context, dirs []string) []ExportRepo {
+	var mtx sync.Mutex
+	repos := make([]ExportRepo, 0, len(dirs))
 	prefix := parsePath(flags.path) + "/"
 
-	for _, dir := range dirs {
-		go func(dir string) {
-			defer wg.Done()
-			data := getGitRepo(dir)
-			dir = strings.TrimPrefix(strings.TrimSuffix(dir, "/.git"), prefix)
+	pool.Run(ctx, flags.jobs, dirs, func(ctx context.Context, dir string) {
+		repo, err := inspectRepo(ctx, dir)
+		if err != nil {
+			slog.Error("error in inspecting git repo for dir", "dir", dir, "error", err)
+			return
+		}
+		repo.Path = strings.TrimPrefix(strings.TrimSuffix(dir, "/.git"), prefix)
+
+		mtx.Lock()
+		repos = append(repos, repo)
+		mtx.Unlock()
+	})
+
+	return repos
+}
+
+// inspectRepo reads everything ExportRepo needs out of the repo at dir using
+// the git CLI directly, since none of it is exposed by the VCS interface.
+func inspectRepo(ctx context.Context, dir string) (ExportRepo, error) {
+	remotes, err := gitRemotes(ctx, dir)
+	if err != nil {
+		return ExportRepo{}, err
+	}
+
+	branch, err := gitOutput(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return ExportRepo{}, err
+	}
 
-			mtx.Lock()
-			jsonData[dir] = data
-			mtx.Unlock()
-		}(dir)
+	head, err := gitOutput(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return ExportRepo{}, err
 	}
-	wg.Wait()
-	return jsonData
+
+	return ExportRepo{
+		Remotes:       remotes,
+		DefaultBranch: branch,
+		Head:          head,
+		Submodules:    gitSubmodules(ctx, dir),
+		LFS:           usesLFS(dir),
+	}, nil
 }
 
-// // getGitRepo function gets the git repository from the directory
-func getGitRepo(dir string) string {
-	output, err := exec.Command("git", "-C", dir, "config", "--get", "remote.origin.url").Output()
+// gitRemotes returns every remote configured in dir, keyed by name, via
+// `git remote -v`.
+func gitRemotes(ctx context.Context, dir string) (map[string]string, error) {
+	output, err := gitOutput(ctx, dir, "remote", "-v")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	remotes := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		remotes[fields[0]] = fields[1]
+	}
+	return remotes, nil
+}
+
+// gitSubmodules parses dir/.gitmodules via `git config -f .gitmodules
+// --get-regexp`, returning nil if the repo has no submodules.
+func gitSubmodules(ctx context.Context, dir string) []Submodule {
+	output, err := exec.CommandContext(ctx, "git", "config", "-f", dir+"/.gitmodules", "--get-regexp", `submodule\..*\.(path|url)`).Output()
+	if err != nil {
+		return nil
+	}
+
+	paths := make(map[string]string)
+	urls := make(map[string]string)
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		key, value := fields[0], fields[1]
+		name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(key, ".path"), ".url"), "submodule.")
+		switch {
+		case strings.HasSuffix(key, ".path"):
+			paths[name] = value
+		case strings.HasSuffix(key, ".url"):
+			urls[name] = value
+		}
+	}
+
+	var submodules []Submodule
+	for name, path := range paths {
+		submodules = append(submodules, Submodule{Path: path, URL: urls[name]})
+	}
+	return submodules
+}
+
+// usesLFS reports whether dir's .gitattributes declares a git-lfs filter.
+func usesLFS(dir string) bool {
+	data, err := os.ReadFile(dir + "/.gitattributes")
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "filter=lfs")
+}
+
+// gitOutput runs `git -C dir <args...>` and returns its trimmed stdout.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", err
 	}
-	return strings.TrimSpace(string(output))
+	return strings.TrimSpace(string(output)), nil
 }
 
 // saveFile function saves the data to a file
 func saveFile(filename string, data []byte) {
-	if err := os.WriteFile(filename, data, 0644); err != nil {
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
 		log.Fatal(err)
 	}
 }
 
-// exportJSON function exports the data to a JSON file
-func exportJSON(data map[string]string) {
-	result, err := json.MarshalIndent(data, "", "  ")
+// exportJSON function exports the data to a versioned JSON file
+func exportJSON(repos []ExportRepo) {
+	result, err := json.MarshalIndent(ExportData{Version: exportVersion, Repos: repos}, "", "  ")
 	if err != nil {
 		log.Fatal(err)
 	}
 	fileName := flags.fileName
 	if fileName == "" {
-		fileName = "export"
+		fileName = "export.json"
 	} else if !strings.HasSuffix(fileName, ".json") {
 		fileName += ".json"
 	}
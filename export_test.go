@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGitSubmodules(t *testing.T) {
+	dir := t.TempDir()
+	gitmodules := "" +
+		"[submodule \"vendor/lib\"]\n" +
+		"\tpath = vendor/lib\n" +
+		"\turl = https://example.com/lib.git\n" +
+		"[submodule \"tools\"]\n" +
+		"\tpath = tools\n" +
+		"\turl = git@example.com:org/tools.git\n"
+
+	if err := os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte(gitmodules), 0o644); err != nil {
+		t.Fatalf("writing .gitmodules: %v", err)
+	}
+
+	got := gitSubmodules(context.Background(), dir)
+	sort.Slice(got, func(i, j int) bool { return got[i].Path < got[j].Path })
+
+	want := []Submodule{
+		{Path: "tools", URL: "git@example.com:org/tools.git"},
+		{Path: "vendor/lib", URL: "https://example.com/lib.git"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("gitSubmodules() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGitSubmodulesNoFile(t *testing.T) {
+	dir := t.TempDir()
+
+	if got := gitSubmodules(context.Background(), dir); got != nil {
+		t.Fatalf("gitSubmodules() = %+v, want nil", got)
+	}
+}
+
+func TestUsesLFS(t *testing.T) {
+	dir := t.TempDir()
+
+	if usesLFS(dir) {
+		t.Fatal("usesLFS() = true for repo with no .gitattributes, want false")
+	}
+
+	attrs := "*.bin filter=lfs diff=lfs merge=lfs -text\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte(attrs), 0o644); err != nil {
+		t.Fatalf("writing .gitattributes: %v", err)
+	}
+
+	if !usesLFS(dir) {
+		t.Fatal("usesLFS() = false for repo with a filter=lfs rule, want true")
+	}
+}
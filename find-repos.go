@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sync"
 	"sync/atomic"
 )
 
-func FindGitReposParallel(root string, workers int) ([]string, error) {
+// FindGitReposParallel walks root looking for git repositories (directories
+// containing a ".git" subdir, or themselves a bare repo), fanning the walk
+// out across workers goroutines. It stops discovering new directories as
+// soon as ctx is cancelled, returning whatever it found so far along with
+// ctx.Err().
+func FindGitReposParallel(ctx context.Context, root string, workers int) ([]string, error) {
 	type job struct{ path string }
 
 	var wg sync.WaitGroup
@@ -23,6 +29,13 @@ func FindGitReposParallel(root string, workers int) ([]string, error) {
 		defer wg.Done()
 
 		for j := range jobs {
+			if ctx.Err() != nil {
+				if active.Add(-1) == 0 {
+					close(jobs)
+				}
+				continue
+			}
+
 			entries, err := os.ReadDir(j.path)
 			if err != nil {
 				if active.Add(-1) == 0 {
@@ -32,7 +45,7 @@ func FindGitReposParallel(root string, workers int) ([]string, error) {
 				continue
 			}
 
-			// Look for .git
+			// Look for .git, or for the directory itself being a bare repo
 			var found bool
 			for _, e := range entries {
 				if e.IsDir() && e.Name() == ".git" {
@@ -44,12 +57,23 @@ func FindGitReposParallel(root string, workers int) ([]string, error) {
 				}
 			}
 
+			if !found && isBareRepo(entries) {
+				resultsMu.Lock()
+				results = append(results, j.path)
+				resultsMu.Unlock()
+				found = true
+			}
+
 			// Prune at repo root
 			if !found {
 				for _, e := range entries {
 					if e.IsDir() {
 						active.Add(1)
-						jobs <- job{filepath.Join(j.path, e.Name())}
+						select {
+						case jobs <- job{filepath.Join(j.path, e.Name())}:
+						case <-ctx.Done():
+							active.Add(-1)
+						}
 					}
 				}
 			}
@@ -68,10 +92,40 @@ func FindGitReposParallel(root string, workers int) ([]string, error) {
 	}
 
 	// Seed root
+	active.Add(1)
 	jobs <- job{root}
 
 	// Wait for workers
 	wg.Wait()
 
-	return results, nil
+	return results, ctx.Err()
+}
+
+// isBareRepoDir reports whether dir is itself the top level of a bare git
+// repo. Unreadable directories are reported as not bare.
+func isBareRepoDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	return isBareRepo(entries)
+}
+
+// isBareRepo reports whether entries (the contents of a directory) look
+// like the top level of a bare git repo, i.e. a "*.git" directory created
+// by `git clone --bare`/`--mirror` rather than a working copy with a
+// nested ".git".
+func isBareRepo(entries []os.DirEntry) bool {
+	var hasHEAD, hasObjects, hasRefs bool
+	for _, e := range entries {
+		switch {
+		case !e.IsDir() && e.Name() == "HEAD":
+			hasHEAD = true
+		case e.IsDir() && e.Name() == "objects":
+			hasObjects = true
+		case e.IsDir() && e.Name() == "refs":
+			hasRefs = true
+		}
+	}
+	return hasHEAD && hasObjects && hasRefs
 }
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"sync"
+
+	"github.com/AbhinavAchha/syncrepo/internal/pool"
+	"github.com/AbhinavAchha/syncrepo/internal/report"
+	"github.com/AbhinavAchha/syncrepo/internal/vcs"
 )
 
-func importJSON(filename string) (jsonData map[string]string) {
+// importJSON reads filename as a versioned ExportData document. For
+// backward compatibility it also accepts the old format: a flat
+// path->origin-url map, with no "version"/"repos" wrapper.
+func importJSON(filename string) []ExportRepo {
 	if filename == "" {
 		log.Default().Println("No filename specified, using 'export.json'")
 		filename = "export.json"
@@ -17,35 +27,135 @@ func importJSON(filename string) (jsonData map[string]string) {
 	if err != nil {
 		log.Fatal("Error reading file: ", err)
 	}
-	if err = json.Unmarshal(data, &jsonData); err != nil {
+
+	var export ExportData
+	if err := json.Unmarshal(data, &export); err == nil && export.Version > 0 {
+		return export.Repos
+	}
+
+	var flat map[string]string
+	if err := json.Unmarshal(data, &flat); err != nil {
 		log.Fatal(err)
 	}
-	return jsonData
+
+	repos := make([]ExportRepo, 0, len(flat))
+	for path, url := range flat {
+		repos = append(repos, ExportRepo{Path: path, Remotes: map[string]string{"origin": url}})
+	}
+	return repos
 }
 
-func createRepos(data map[string]string) {
-	wg := sync.WaitGroup{}
-	wg.Add(len(data))
+// createRepos clones every repo in repos under flags.path, using a bounded
+// pool of workers. A failure is recorded in the returned Report rather than
+// aborting the rest of the run.
+func createRepos(ctx context.Context, backend vcs.VCS, repos []ExportRepo) *report.Report {
+	rep := &report.Report{}
 	importPath := parsePath(flags.path)
-	for dir, url := range data {
-		go func(dir, url string) {
-			defer wg.Done()
 
-			if err := os.MkdirAll(importPath+"/"+dir, 0755); err != nil {
-				log.Fatal(err)
-			}
-			clone(dir, url)
-		}(dir, url)
+	processed := make(map[string]bool)
+	var mu sync.Mutex
+
+	pool.Run(ctx, flags.jobs, repos, func(ctx context.Context, repo ExportRepo) {
+		mu.Lock()
+		processed[repo.Path] = true
+		mu.Unlock()
+
+		if o, ok := cfg.OverrideFor(repo.Path); ok && o.Skip {
+			slog.Info("skipping repo excluded by config", "repo", repo.Path)
+			return
+		}
+
+		dest := importPath + "/" + repo.Path
+
+		if err := os.MkdirAll(dest, 0o755); err != nil {
+			rep.Add(repo.Path, fmt.Errorf("creating directory %s: %w", dest, err))
+			return
+		}
+		rep.Add(repo.Path, restoreRepo(ctx, backend, dest, repo))
+	})
+
+	for _, repo := range repos {
+		if !processed[repo.Path] {
+			rep.Add(repo.Path, fmt.Errorf("not processed: %w", ctx.Err()))
+		}
+	}
+
+	return rep
+}
+
+// restoreRepo clones repo's origin remote (or the override remote, if
+// configured) into dest, then restores every extra remote, the checked-out
+// branch, submodules and git-lfs content that ExportRepo recorded.
+func restoreRepo(ctx context.Context, backend vcs.VCS, dest string, repo ExportRepo) error {
+	override, _ := cfg.OverrideFor(repo.Path)
+
+	cloneRemote := "origin"
+	if override.Remote != "" {
+		cloneRemote = override.Remote
+	}
+
+	url := repo.Remotes[cloneRemote]
+	if url == "" {
+		return fmt.Errorf("no %s remote recorded for %s", cloneRemote, repo.Path)
+	}
+
+	depth := flags.depth
+	if override.Depth != 0 {
+		depth = override.Depth
+	}
+
+	if err := backend.Clone(ctx, url, dest, vcs.CloneOptions{Bare: flags.bare, Mirror: flags.mirror, Depth: depth}); err != nil {
+		return err
+	}
+
+	// Clone always names the cloned remote "origin"; give it its recorded
+	// name so the recorded origin can be added back below.
+	if cloneRemote != "origin" {
+		if err := gitRun(ctx, dest, "remote", "rename", "origin", cloneRemote); err != nil {
+			return fmt.Errorf("renaming remote origin to %s: %w", cloneRemote, err)
+		}
+	}
+
+	for name, remoteURL := range repo.Remotes {
+		if name == cloneRemote {
+			continue
+		}
+		if err := gitRun(ctx, dest, "remote", "add", name, remoteURL); err != nil {
+			return fmt.Errorf("adding remote %s: %w", name, err)
+		}
+	}
 
+	branch := repo.DefaultBranch
+	if override.Branch != "" {
+		branch = override.Branch
 	}
-	wg.Wait()
+
+	// Bare/mirror clones have no working tree to check a branch out into.
+	if branch != "" && !flags.bare && !flags.mirror {
+		if err := gitRun(ctx, dest, "checkout", branch); err != nil {
+			return fmt.Errorf("checking out %s: %w", branch, err)
+		}
+	}
+
+	if len(repo.Submodules) > 0 {
+		if err := gitRun(ctx, dest, "submodule", "update", "--init", "--recursive"); err != nil {
+			return fmt.Errorf("updating submodules: %w", err)
+		}
+	}
+
+	if repo.LFS {
+		if err := gitRun(ctx, dest, "lfs", "pull"); err != nil {
+			return fmt.Errorf("pulling lfs content: %w", err)
+		}
+	}
+
+	return nil
 }
 
-func clone(dir, url string) {
-	cmd := exec.Command("git", "clone", url, dir)
+// gitRun runs `git -C dir <args...>`, streaming its output to stdout/stderr.
+func gitRun(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		log.Fatal(err)
-	}
+	return cmd.Run()
 }
@@ -0,0 +1,101 @@
+// Package config loads syncrepo's optional .syncrepo.yaml file. It supplies
+// defaults for flags the user didn't pass on the command line, and
+// per-directory overrides for things no flag can express at all (which
+// branch a specific repo should track, an alternate remote, a hook to run
+// after pulling it, or skipping it entirely).
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderSync describes one provider account to import from, mirroring the
+// -provider/-user/-token/-include-* flags.
+type ProviderSync struct {
+	Name            string `yaml:"name"`
+	Account         string `yaml:"account"`
+	Token           string `yaml:"token"`
+	IncludeForks    bool   `yaml:"include_forks"`
+	IncludePrivate  bool   `yaml:"include_private"`
+	IncludeArchived bool   `yaml:"include_archived"`
+	NameRegexp      string `yaml:"name_regexp"`
+}
+
+// Override holds the settings a single directory can override away from the
+// built-in/config-file defaults.
+type Override struct {
+	// Branch is the branch this repo should be checked out/tracking,
+	// overriding the recorded default_branch on import.
+	Branch string `yaml:"branch"`
+	// Remote is an alternate recorded remote name to clone from on import
+	// instead of "origin". Pull and fetch do not read it.
+	Remote string `yaml:"remote"`
+	// PostPull is a shell command run in the repo's directory after a
+	// successful pull or fetch.
+	PostPull string `yaml:"post_pull"`
+	// Depth overrides the default clone depth for this repo when non-zero.
+	// 0 leaves the default depth in place, so an override cannot force full
+	// history when a default depth is set.
+	Depth int `yaml:"depth"`
+	// Skip excludes this repo from every subcommand.
+	Skip bool `yaml:"skip"`
+}
+
+// Config is the root of a .syncrepo.yaml file.
+type Config struct {
+	Path    string `yaml:"path"`
+	Jobs    int    `yaml:"jobs"`
+	Backend string `yaml:"backend"`
+	// Depth is the default shallow-clone depth; 0 means full history.
+	Depth int `yaml:"depth"`
+	// Exclude holds filepath.Match globs, matched against each repo's path
+	// relative to Path.
+	Exclude []string `yaml:"exclude"`
+
+	Providers []ProviderSync `yaml:"providers"`
+
+	// Overrides is keyed by repo path, relative to Path.
+	Overrides map[string]Override `yaml:"overrides"`
+}
+
+// Load reads and parses a .syncrepo.yaml file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: reading %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// OverrideFor returns the override configured for dir, if any. A nil
+// receiver (no config file loaded) always reports no override.
+func (c *Config) OverrideFor(dir string) (Override, bool) {
+	if c == nil {
+		return Override{}, false
+	}
+	o, ok := c.Overrides[dir]
+	return o, ok
+}
+
+// Excluded reports whether dir matches one of Exclude's globs. A nil
+// receiver never excludes anything.
+func (c *Config) Excluded(dir string) bool {
+	if c == nil {
+		return false
+	}
+	for _, pattern := range c.Exclude {
+		if ok, err := filepath.Match(pattern, dir); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
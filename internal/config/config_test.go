@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".syncrepo.yaml")
+	data := "" +
+		"path: /repos\n" +
+		"jobs: 4\n" +
+		"backend: go-git\n" +
+		"depth: 1\n" +
+		"exclude:\n" +
+		"  - \"vendor/*\"\n" +
+		"overrides:\n" +
+		"  tools:\n" +
+		"    branch: develop\n" +
+		"    skip: true\n"
+
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Path != "/repos" || cfg.Jobs != 4 || cfg.Backend != "go-git" || cfg.Depth != 1 {
+		t.Fatalf("got %+v, want defaults parsed from yaml", cfg)
+	}
+
+	o, ok := cfg.OverrideFor("tools")
+	if !ok || o.Branch != "develop" || !o.Skip {
+		t.Fatalf("OverrideFor(tools) = %+v, %v, want branch develop + skip", o, ok)
+	}
+
+	if !cfg.Excluded("vendor/lib") {
+		t.Fatal("Excluded(vendor/lib) = false, want true")
+	}
+	if cfg.Excluded("src/lib") {
+		t.Fatal("Excluded(src/lib) = true, want false")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("Load() on a missing file = nil error, want an error")
+	}
+}
+
+func TestNilConfigTolerated(t *testing.T) {
+	var cfg *Config
+
+	if cfg.Excluded("anything") {
+		t.Fatal("nil Config.Excluded() = true, want false")
+	}
+	if _, ok := cfg.OverrideFor("anything"); ok {
+		t.Fatal("nil Config.OverrideFor() reported an override, want none")
+	}
+}
@@ -0,0 +1,45 @@
+// Package pool provides a small bounded worker pool for fanning a function
+// out over a list of items, replacing the "one goroutine per repo" pattern
+// that made every syncrepo subcommand spawn an unbounded number of
+// goroutines and `git` processes.
+package pool
+
+import (
+	"context"
+	"sync"
+)
+
+// Run executes fn once per item in items, using at most size concurrent
+// workers. It returns once every item has either run or been skipped
+// because ctx was cancelled. The order in which items are processed is not
+// guaranteed.
+func Run[T any](ctx context.Context, size int, items []T, fn func(ctx context.Context, item T)) {
+	if size <= 0 {
+		size = 1
+	}
+
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(size)
+
+	for i := 0; i < size; i++ {
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				fn(ctx, item)
+			}
+		}()
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case jobs <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+}
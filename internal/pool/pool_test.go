@@ -0,0 +1,57 @@
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunProcessesEveryItem(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	var seen atomic.Int64
+
+	Run(context.Background(), 2, items, func(ctx context.Context, item int) {
+		seen.Add(1)
+	})
+
+	if got := seen.Load(); got != int64(len(items)) {
+		t.Fatalf("got %d items processed, want %d", got, len(items))
+	}
+}
+
+func TestRunZeroSizeFallsBackToOne(t *testing.T) {
+	items := []int{1, 2, 3}
+	var seen atomic.Int64
+
+	Run(context.Background(), 0, items, func(ctx context.Context, item int) {
+		seen.Add(1)
+	})
+
+	if got := seen.Load(); got != int64(len(items)) {
+		t.Fatalf("got %d items processed, want %d", got, len(items))
+	}
+}
+
+func TestRunStopsFeedingAfterCancel(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var seen atomic.Int64
+
+	Run(ctx, 1, items, func(ctx context.Context, item int) {
+		if seen.Add(1) == 1 {
+			cancel()
+		}
+		// Give the feed loop a chance to observe the cancellation instead
+		// of racing it with the rest of a near-instant items slice.
+		time.Sleep(time.Millisecond)
+	})
+
+	if got := seen.Load(); got >= int64(len(items)) {
+		t.Fatalf("got %d items processed after cancel, want fewer than %d", got, len(items))
+	}
+}
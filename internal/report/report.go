@@ -0,0 +1,64 @@
+// Package report aggregates the per-repository outcome of a syncrepo run
+// (pull, export, import, ...) so it can be printed as a summary or written
+// out as JSON, instead of the first failure calling log.Fatal and aborting
+// every other repo in flight.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Result is the outcome of processing a single repository.
+type Result struct {
+	Path string `json:"path"`
+	Err  string `json:"error,omitempty"`
+}
+
+// Report aggregates Results from concurrent workers. The zero value is
+// ready to use.
+type Report struct {
+	mu      sync.Mutex
+	Results []Result `json:"results"`
+}
+
+// Add records the outcome for path. It is safe to call concurrently.
+func (r *Report) Add(path string, err error) {
+	res := Result{Path: path}
+	if err != nil {
+		res.Err = err.Error()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Results = append(r.Results, res)
+}
+
+// Failed returns the results that recorded an error.
+func (r *Report) Failed() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Err != "" {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// PrintSummary writes a one-line-per-failure human summary to w.
+func (r *Report) PrintSummary(w io.Writer) {
+	failed := r.Failed()
+	fmt.Fprintf(w, "%d succeeded, %d failed out of %d\n", len(r.Results)-len(failed), len(failed), len(r.Results))
+	for _, res := range failed {
+		fmt.Fprintf(w, "  FAILED %s: %s\n", res.Path, res.Err)
+	}
+}
+
+// WriteJSON marshals the report as indented JSON to w.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAddAndFailed(t *testing.T) {
+	var r Report
+	r.Add("repo-a", nil)
+	r.Add("repo-b", errors.New("boom"))
+
+	failed := r.Failed()
+	if len(failed) != 1 {
+		t.Fatalf("got %d failed results, want 1", len(failed))
+	}
+	if failed[0].Path != "repo-b" || failed[0].Err != "boom" {
+		t.Fatalf("got %+v, want repo-b/boom", failed[0])
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	var r Report
+	r.Add("repo-a", nil)
+	r.Add("repo-b", errors.New("boom"))
+
+	var buf bytes.Buffer
+	r.PrintSummary(&buf)
+
+	out := buf.String()
+	if !strings.Contains(out, "1 succeeded, 1 failed out of 2") {
+		t.Fatalf("summary missing counts: %q", out)
+	}
+	if !strings.Contains(out, "FAILED repo-b: boom") {
+		t.Fatalf("summary missing failure line: %q", out)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var r Report
+	r.Add("repo-a", nil)
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"path": "repo-a"`) {
+		t.Fatalf("JSON missing path field: %q", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+package vcs
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gogithttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// authFor returns the auth method to use for remoteURL: an SSH key for
+// ssh:// and scp-like "git@host:path" URLs, or an HTTP token from
+// SYNCREPO_TOKEN/SYNCREPO_<SCHEME>_TOKEN or ~/.netrc for http(s):// URLs.
+// A nil AuthMethod is returned (with no error) for anonymous access.
+func authFor(remoteURL string) (transport.AuthMethod, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Scheme == "" || u.Scheme == "ssh" {
+		return sshAuth()
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		return httpAuth(u)
+	}
+
+	return nil, nil
+}
+
+func sshAuth() (transport.AuthMethod, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("vcs: resolving home directory: %w", err)
+	}
+
+	keyPath := filepath.Join(home, ".ssh", "id_ed25519")
+	if _, err := os.Stat(keyPath); err != nil {
+		keyPath = filepath.Join(home, ".ssh", "id_rsa")
+	}
+	if _, err := os.Stat(keyPath); err != nil {
+		// No key on disk: let go-git fall back to its default SSH agent auth.
+		return nil, nil
+	}
+
+	auth, err := ssh.NewPublicKeysFromFile("git", keyPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("vcs: loading SSH key %s: %w", keyPath, err)
+	}
+	return auth, nil
+}
+
+func httpAuth(u *url.URL) (transport.AuthMethod, error) {
+	if token := os.Getenv("SYNCREPO_" + strings.ToUpper(u.Scheme) + "_TOKEN"); token != "" {
+		return &gogithttp.BasicAuth{Username: "token", Password: token}, nil
+	}
+
+	if token := os.Getenv("SYNCREPO_TOKEN"); token != "" {
+		return &gogithttp.BasicAuth{Username: "token", Password: token}, nil
+	}
+
+	if user, pass, ok := netrcAuth(u.Host); ok {
+		return &gogithttp.BasicAuth{Username: user, Password: pass}, nil
+	}
+
+	return nil, nil
+}
+
+// netrcAuth looks up a login/password pair for host in ~/.netrc.
+func netrcAuth(host string) (login, password string, ok bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", false
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".netrc"))
+	if err != nil {
+		return "", "", false
+	}
+
+	tokens := strings.Fields(string(data))
+	var inMachine bool
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			i++
+			if i >= len(tokens) {
+				break
+			}
+			inMachine = tokens[i] == host
+			login, password = "", ""
+		case "login":
+			i++
+			if inMachine && i < len(tokens) {
+				login = tokens[i]
+			}
+		case "password":
+			i++
+			if inMachine && i < len(tokens) {
+				password = tokens[i]
+			}
+		}
+		if inMachine && login != "" && password != "" {
+			return login, password, true
+		}
+	}
+
+	return "", "", false
+}
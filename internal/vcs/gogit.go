@@ -0,0 +1,118 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// GoGitVCS implements VCS with an in-process go-git client, avoiding the
+// fork/exec overhead of shelling out to the git binary and letting syncrepo
+// run on hosts without git installed.
+type GoGitVCS struct{}
+
+func (GoGitVCS) Clone(ctx context.Context, url, dir string, opts CloneOptions) error {
+	auth, err := authFor(url)
+	if err != nil {
+		return err
+	}
+
+	_, err = git.PlainCloneContext(ctx, dir, opts.Bare, &git.CloneOptions{
+		URL:    url,
+		Depth:  opts.Depth,
+		Auth:   auth,
+		Mirror: opts.Mirror,
+	})
+	if err != nil {
+		return fmt.Errorf("go-git: cloning %s: %w", url, err)
+	}
+	return nil
+}
+
+func (GoGitVCS) Pull(ctx context.Context, dir string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("go-git: opening %s: %w", dir, err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("go-git: worktree for %s: %w", dir, err)
+	}
+
+	auth, err := remoteAuth(repo)
+	if err != nil {
+		return err
+	}
+
+	err = wt.PullContext(ctx, &git.PullOptions{Auth: auth})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("go-git: pulling %s: %w", dir, err)
+	}
+	return nil
+}
+
+// Fetch fetches every remote configured in dir, matching ShellVCS.Fetch's
+// `git fetch --all --prune --tags` rather than hardcoding "origin".
+func (GoGitVCS) Fetch(ctx context.Context, dir string) error {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return fmt.Errorf("go-git: opening %s: %w", dir, err)
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return fmt.Errorf("go-git: listing remotes for %s: %w", dir, err)
+	}
+
+	for _, remote := range remotes {
+		name := remote.Config().Name
+
+		auth, err := authFor(firstURL(remote))
+		if err != nil {
+			return err
+		}
+
+		err = repo.FetchContext(ctx, &git.FetchOptions{
+			RemoteName: name,
+			Auth:       auth,
+			Tags:       git.AllTags,
+			Prune:      true,
+		})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return fmt.Errorf("go-git: fetching %s from %s: %w", dir, name, err)
+		}
+	}
+	return nil
+}
+
+func (GoGitVCS) RemoteURL(ctx context.Context, dir string) (string, error) {
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		return "", fmt.Errorf("go-git: opening %s: %w", dir, err)
+	}
+
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("go-git: remote for %s: %w", dir, err)
+	}
+	return firstURL(remote), nil
+}
+
+func remoteAuth(repo *git.Repository) (transport.AuthMethod, error) {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return nil, fmt.Errorf("go-git: remote: %w", err)
+	}
+	return authFor(firstURL(remote))
+}
+
+func firstURL(remote *git.Remote) string {
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
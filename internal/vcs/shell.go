@@ -0,0 +1,61 @@
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ShellVCS implements VCS by shelling out to the git CLI, same as syncrepo
+// has always done.
+type ShellVCS struct{}
+
+func (ShellVCS) Clone(ctx context.Context, url, dir string, opts CloneOptions) error {
+	args := []string{"clone", url, dir}
+	if opts.Mirror {
+		args = append(args, "--mirror")
+	} else if opts.Bare {
+		args = append(args, "--bare")
+	}
+	if opts.Depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", opts.Depth))
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cloning %s: %w", url, err)
+	}
+	return nil
+}
+
+func (ShellVCS) Pull(ctx context.Context, dir string) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "pull", "--depth=1")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("pulling %s: %w", dir, err)
+	}
+	return nil
+}
+
+func (ShellVCS) Fetch(ctx context.Context, dir string) error {
+	cmd := exec.CommandContext(ctx, "git", "-C", dir, "fetch", "--all", "--prune", "--tags")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("fetching %s: %w", dir, err)
+	}
+	return nil
+}
+
+func (ShellVCS) RemoteURL(ctx context.Context, dir string) (string, error) {
+	output, err := exec.CommandContext(ctx, "git", "-C", dir, "config", "--get", "remote.origin.url").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading remote url for %s: %w", dir, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
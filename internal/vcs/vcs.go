@@ -0,0 +1,39 @@
+// Package vcs abstracts the mechanics of cloning, pulling and fetching a
+// git repository behind a single interface, so that syncrepo can shell out
+// to the git CLI or drive an in-process go-git client interchangeably.
+package vcs
+
+import (
+	"context"
+	"fmt"
+)
+
+// CloneOptions configures a Clone call.
+type CloneOptions struct {
+	Bare   bool
+	Mirror bool
+	// Depth requests a shallow clone. Zero means full history.
+	Depth int
+}
+
+// VCS clones, pulls and fetches a single repository, and reports the URL it
+// is configured to sync with.
+type VCS interface {
+	Clone(ctx context.Context, url, dir string, opts CloneOptions) error
+	Pull(ctx context.Context, dir string) error
+	Fetch(ctx context.Context, dir string) error
+	RemoteURL(ctx context.Context, dir string) (string, error)
+}
+
+// New returns the VCS implementation named by backend: "git" (the default,
+// shells out to the git CLI) or "go-git" (in-process, via go-git).
+func New(backend string) (VCS, error) {
+	switch backend {
+	case "", "git":
+		return ShellVCS{}, nil
+	case "go-git":
+		return GoGitVCS{}, nil
+	default:
+		return nil, fmt.Errorf("vcs: unknown backend %q", backend)
+	}
+}
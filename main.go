@@ -5,7 +5,7 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"flag"
 	"fmt"
 	"io/fs"
@@ -19,6 +19,12 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+
+	"github.com/AbhinavAchha/syncrepo/internal/config"
+	"github.com/AbhinavAchha/syncrepo/internal/pool"
+	"github.com/AbhinavAchha/syncrepo/internal/report"
+	"github.com/AbhinavAchha/syncrepo/internal/vcs"
+	"github.com/AbhinavAchha/syncrepo/providers"
 )
 
 var flags struct {
@@ -29,8 +35,33 @@ var flags struct {
 	help     bool
 	export   bool
 	toImport bool
+	fetch    bool
+
+	bare   bool
+	mirror bool
+	depth  int
+
+	provider        string
+	account         string
+	token           string
+	includeForks    bool
+	includePrivate  bool
+	includeArchived bool
+	nameRegexp      string
+	giteaURL        string
+
+	jobs       int
+	reportFile string
+	backend    string
+
+	config string
 }
 
+// cfg is the parsed .syncrepo.yaml file, or nil if -config wasn't given.
+// Every method on *config.Config tolerates a nil receiver, so call sites
+// don't need to special-case "no config file" separately.
+var cfg *config.Config
+
 func main() {
 	flag.StringVar(&flags.path, "path", ".", "Path to the directory containing git repositories")
 	flag.StringVar(&flags.fileName, "file", "", "File name to save the list of git repositories")
@@ -39,6 +70,22 @@ func main() {
 	flag.BoolVar(&flags.help, "help", false, "Show help")
 	flag.BoolVar(&flags.export, "export", false, "Export all the git repositories to a JSON file")
 	flag.BoolVar(&flags.toImport, "import", false, "Import all the git repositories from a JSON file")
+	flag.BoolVar(&flags.fetch, "fetch", false, "Fetch (git fetch --all --prune --tags) all the git repositories instead of pulling")
+	flag.BoolVar(&flags.bare, "bare", false, "Clone imported repositories as bare repos, for a backup tree")
+	flag.BoolVar(&flags.mirror, "mirror", false, "Clone imported repositories as mirrors (implies -bare)")
+	flag.StringVar(&flags.provider, "provider", "", "Git host to import from instead of a JSON file: github, gitlab, gitea or bitbucket")
+	flag.StringVar(&flags.account, "user", "", "User, org, group or workspace to enumerate on the provider")
+	flag.StringVar(&flags.token, "token", "", "Access token for the provider")
+	flag.BoolVar(&flags.includeForks, "include-forks", false, "Include forked repositories when importing from a provider")
+	flag.BoolVar(&flags.includePrivate, "include-private", false, "Include private repositories when importing from a provider")
+	flag.BoolVar(&flags.includeArchived, "include-archived", false, "Include archived repositories when importing from a provider")
+	flag.StringVar(&flags.nameRegexp, "name-regexp", "", "Only import repositories whose name matches this regexp")
+	flag.StringVar(&flags.giteaURL, "gitea-url", "", "API root of a self-hosted Gitea instance, e.g. https://git.example.com/api/v1 (defaults to gitea.com)")
+	flag.IntVar(&flags.jobs, "jobs", runtime.NumCPU(), "Number of repos to process concurrently")
+	flag.StringVar(&flags.reportFile, "report-file", "", "Write a JSON report of per-repo results to this file")
+	flag.StringVar(&flags.backend, "backend", "git", "VCS backend to use: git (shell out to the git CLI) or go-git (in-process)")
+	flag.IntVar(&flags.depth, "depth", 0, "Clone depth for imported repositories; 0 means full history")
+	flag.StringVar(&flags.config, "config", "", "Path to a .syncrepo.yaml file of defaults and per-repo overrides")
 	flag.Parse()
 
 	// check if no arguments are specified
@@ -47,14 +94,35 @@ func main() {
 		os.Exit(0)
 	}
 
+	explicitFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+
+	if flags.config != "" {
+		var err error
+		cfg, err = config.Load(flags.config)
+		if err != nil {
+			log.Fatalf("Error loading config %s: %v", flags.config, err)
+		}
+		applyConfigDefaults(explicitFlags)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	backend, err := vcs.New(flags.backend)
+	if err != nil {
+		log.Fatalf("Error setting up VCS backend: %v", err)
+	}
+
 	path := parsePath(flags.path)
-	list, err := FindGitReposParallel(path, runtime.NumCPU())
+	list, err := FindGitReposParallel(ctx, path, runtime.NumCPU())
 	if err != nil {
 		log.Fatalf("Error in finding git repositories: %v", err)
 	}
+	list = filterExcluded(list)
 
 	if flags.list {
-		urls := getGitRepos(list)
+		urls := getGitRepos(ctx, backend, list)
 		if flags.fileName != "" {
 			saveToFile(flags.fileName, urls)
 		} else {
@@ -63,23 +131,181 @@ func main() {
 	}
 
 	if flags.pull {
-		pullGitRepos(list)
+		rep := pullGitRepos(ctx, backend, list)
+		writeReport(rep)
+		os.Exit(0)
+	}
+
+	if flags.fetch {
+		rep := fetchGitRepos(ctx, backend, list)
+		writeReport(rep)
 		os.Exit(0)
 	}
 
 	if flags.export {
-		repoData := getExportData(list)
+		repoData := getExportData(ctx, list)
 		exportJSON(repoData)
 		os.Exit(0)
 	}
 
 	if flags.toImport {
-		jsonData := importJSON(flags.fileName)
-		createRepos(jsonData)
+		var repos []ExportRepo
+		switch {
+		case flags.provider != "":
+			repos = importFromProvider(ctx)
+		case cfg != nil && len(cfg.Providers) > 0:
+			repos = importFromConfigProviders(ctx)
+		default:
+			repos = importJSON(flags.fileName)
+		}
+		rep := createRepos(ctx, backend, repos)
+		writeReport(rep)
 		os.Exit(0)
 	}
 }
 
+// applyConfigDefaults fills in any flag the user didn't pass explicitly on
+// the command line with cfg's value, per the precedence order CLI flags >
+// config file > built-in defaults. explicitFlags holds the names of flags
+// flag.Visit reported as set.
+func applyConfigDefaults(explicitFlags map[string]bool) {
+	if cfg.Path != "" && !explicitFlags["path"] {
+		flags.path = cfg.Path
+	}
+	if cfg.Jobs != 0 && !explicitFlags["jobs"] {
+		flags.jobs = cfg.Jobs
+	}
+	if cfg.Backend != "" && !explicitFlags["backend"] {
+		flags.backend = cfg.Backend
+	}
+	if cfg.Depth != 0 && !explicitFlags["depth"] {
+		flags.depth = cfg.Depth
+	}
+}
+
+// relPath returns dir expressed relative to flags.path, the form cfg's
+// Exclude globs and Overrides keys are matched against.
+func relPath(dir string) string {
+	base := parsePath(flags.path) + "/"
+	return strings.TrimPrefix(strings.TrimSuffix(dir, "/.git"), base)
+}
+
+// filterExcluded drops any directory in list that cfg excludes, either via
+// an Exclude glob or a per-repo "skip" override. A nil cfg returns list
+// unchanged.
+func filterExcluded(list []string) []string {
+	if cfg == nil {
+		return list
+	}
+
+	filtered := make([]string, 0, len(list))
+	for _, dir := range list {
+		rel := relPath(dir)
+		if cfg.Excluded(rel) {
+			continue
+		}
+		if o, ok := cfg.OverrideFor(rel); ok && o.Skip {
+			continue
+		}
+		filtered = append(filtered, dir)
+	}
+	return filtered
+}
+
+// runPostPullHook runs the post_pull override command configured for dir,
+// if any, after a successful pull or fetch.
+func runPostPullHook(ctx context.Context, dir string) {
+	if cfg == nil {
+		return
+	}
+
+	o, ok := cfg.OverrideFor(relPath(dir))
+	if !ok || o.PostPull == "" {
+		return
+	}
+
+	slog.Info("running post-pull hook", "dir", dir, "cmd", o.PostPull)
+	cmd := exec.CommandContext(ctx, "sh", "-c", o.PostPull)
+	cmd.Dir = strings.TrimSuffix(dir, "/.git")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		slog.Error("post-pull hook failed", "dir", dir, "error", err)
+	}
+}
+
+// writeReport prints rep's summary to stdout and, if -report-file is set,
+// also writes it there as JSON.
+func writeReport(rep *report.Report) {
+	rep.PrintSummary(os.Stdout)
+
+	if flags.reportFile == "" {
+		return
+	}
+
+	f, err := os.Create(flags.reportFile)
+	if err != nil {
+		log.Fatalf("Error in creating report file %s: %v", flags.reportFile, err)
+	}
+	defer f.Close()
+
+	if err := rep.WriteJSON(f); err != nil {
+		log.Fatalf("Error in writing report file %s: %v", flags.reportFile, err)
+	}
+}
+
+// importFromProvider enumerates flags.account on flags.provider and returns
+// one ExportRepo per repo found, with only the origin remote populated.
+func importFromProvider(ctx context.Context) []ExportRepo {
+	return listProviderRepos(ctx, config.ProviderSync{
+		Name:            flags.provider,
+		Account:         flags.account,
+		Token:           flags.token,
+		IncludeForks:    flags.includeForks,
+		IncludePrivate:  flags.includePrivate,
+		IncludeArchived: flags.includeArchived,
+		NameRegexp:      flags.nameRegexp,
+	})
+}
+
+// importFromConfigProviders runs listProviderRepos for every provider in
+// cfg.Providers and returns their combined results. Used when -provider
+// wasn't passed but the config file has a "providers:" block.
+func importFromConfigProviders(ctx context.Context) []ExportRepo {
+	var repos []ExportRepo
+	for _, p := range cfg.Providers {
+		repos = append(repos, listProviderRepos(ctx, p)...)
+	}
+	return repos
+}
+
+// listProviderRepos enumerates p.Account on p.Name and returns one
+// ExportRepo per repo found, with only the origin remote populated.
+func listProviderRepos(ctx context.Context, p config.ProviderSync) []ExportRepo {
+	lister, err := providers.New(p.Name, p.Token, flags.giteaURL)
+	if err != nil {
+		log.Fatalf("Error setting up provider %s: %v", p.Name, err)
+	}
+
+	filter := providers.Filter{
+		IncludeForks:    p.IncludeForks,
+		IncludePrivate:  p.IncludePrivate,
+		IncludeArchived: p.IncludeArchived,
+		NameRegexp:      p.NameRegexp,
+	}
+
+	remoteRepos, err := lister.List(ctx, p.Account, filter)
+	if err != nil {
+		log.Fatalf("Error listing repos for %s on %s: %v", p.Account, p.Name, err)
+	}
+
+	repos := make([]ExportRepo, 0, len(remoteRepos))
+	for name, url := range providers.ToCloneMap(remoteRepos) {
+		repos = append(repos, ExportRepo{Path: name, Remotes: map[string]string{"origin": url}})
+	}
+	return repos
+}
+
 // getDirectories function uses the 'path' argument to get all the directories in the path.
 // It returns a list of directories as a string slice
 func getDirectories(path string) []string {
@@ -116,64 +342,62 @@ func getDirectories(path string) []string {
 }
 
 // getGitRepos function gets the git repositories from the list of directories
-func getGitRepos(list []string) []string {
+func getGitRepos(ctx context.Context, backend vcs.VCS, list []string) []string {
+	var mu sync.Mutex
 	urls := make([]string, 0, len(list))
-	var wg sync.WaitGroup
-	wg.Add(len(list))
 
-	for _, dir := range list {
-		go func(dir string) {
-			defer wg.Done()
-			dir = strings.TrimSuffix(dir, "/.git")
+	pool.Run(ctx, flags.jobs, list, func(ctx context.Context, dir string) {
+		dir = strings.TrimSuffix(dir, "/.git")
 
-			output, err := exec.Command("git", "-C", dir, "config", "--get", "remote.origin.url").Output()
-			if err != nil {
-				slog.Error("error in getting git repo url for dir", "dir", dir, "error", err)
-				return
-			}
+		url, err := backend.RemoteURL(ctx, dir)
+		if err != nil {
+			slog.Error("error in getting git repo url for dir", "dir", dir, "error", err)
+			return
+		}
 
-			urls = append(urls, string(output))
-		}(dir)
-	}
+		mu.Lock()
+		urls = append(urls, url)
+		mu.Unlock()
+	})
 
-	wg.Wait()
 	return urls
 }
 
-// pullGitRepos function uses goroutines to run the 'git -C pull --all' command in parallel
-func pullGitRepos(list []string) {
-	var wg sync.WaitGroup
-	wg.Add(len(list))
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+// pullGitRepos function uses a worker pool to run 'git pull' in every repo in
+// list, retrying failures with a rebase and then a hard reset. It never
+// aborts the run on a single repo's failure; every outcome is recorded in
+// the returned Report.
+func pullGitRepos(ctx context.Context, backend vcs.VCS, list []string) *report.Report {
+	rep := &report.Report{}
 
-	failedRepos := make(map[string]error, 0)
+	failedRepos := make(map[string]error)
+	processed := make(map[string]bool)
 	var mu sync.Mutex
 
-	for _, dir := range list {
-		go func(dir string) {
-			defer wg.Done()
-			if err := runCommand(dir); err != nil {
-				mu.Lock()
-				failedRepos[dir] = err
-				mu.Unlock()
-				slog.Error("error in pulling git repo for dir", "dir", dir, "error", err)
-			}
-		}(dir)
-	}
+	pool.Run(ctx, flags.jobs, list, func(ctx context.Context, dir string) {
+		mu.Lock()
+		processed[dir] = true
+		mu.Unlock()
 
-	select {
-	case <-c:
-		slog.Info("Received interrupt signal, terminating...")
-		os.Exit(1)
-	default:
-		wg.Wait()
-	}
+		if err := runCommand(ctx, backend, dir); err != nil {
+			mu.Lock()
+			failedRepos[dir] = err
+			mu.Unlock()
+			slog.Error("error in pulling git repo for dir", "dir", dir, "error", err)
+			return
+		}
+		runPostPullHook(ctx, dir)
+	})
 
 	for dir, err := range failedRepos {
+		if isBareRepoDir(dir) {
+			// Bare repos have no working tree to rebase or reset.
+			continue
+		}
+
 		// try again by rebasing
 		slog.Info("Retrying pull with rebase for dir coz of error", "dir", dir, "error", err)
-		cmd := exec.Command("git", "-C", dir, "pull", "--rebase", "--depth=1")
+		cmd := exec.CommandContext(ctx, "git", "-C", dir, "pull", "--rebase", "--depth=1")
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 
@@ -181,16 +405,19 @@ func pullGitRepos(list []string) {
 			slog.Error("error in pulling with rebase for dir", "dir", dir, "error", err)
 		} else {
 			slog.Info("Successfully pulled with rebase for dir", "dir", dir)
-			mu.Lock()
 			delete(failedRepos, dir)
-			mu.Unlock()
+			runPostPullHook(ctx, dir)
 		}
 	}
 
 	// try again for failed repos, try reseting git hard
 	for dir, err := range failedRepos {
+		if isBareRepoDir(dir) {
+			continue
+		}
+
 		slog.Info("Retrying pull with reset for dir coz of error", "dir", dir, "error", err)
-		cmd := exec.Command("git", "-C", dir, "reset", "--hard")
+		cmd := exec.CommandContext(ctx, "git", "-C", dir, "reset", "--hard")
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 
@@ -199,27 +426,38 @@ func pullGitRepos(list []string) {
 			continue
 		}
 
-		if err := runCommand(dir); err != nil {
+		if err := runCommand(ctx, backend, dir); err != nil {
 			slog.Error("error in pulling after reset for dir", "dir", dir, "error", err)
 		} else {
 			slog.Info("Successfully pulled after reset for dir", "dir", dir)
-			mu.Lock()
 			delete(failedRepos, dir)
-			mu.Unlock()
+			runPostPullHook(ctx, dir)
 		}
 	}
+
+	for _, dir := range list {
+		if !processed[dir] {
+			rep.Add(dir, fmt.Errorf("not processed: %w", ctx.Err()))
+			continue
+		}
+		rep.Add(dir, failedRepos[dir])
+	}
+	return rep
 }
 
-// runCommand function runs the 'git -C pull --all' command in the directory specified by the 'dir' argument
-func runCommand(dir string) error {
+// runCommand function pulls the git repository in the directory specified by
+// the 'dir' argument. Bare repos have no working tree to pull into, so they
+// are fetched instead.
+func runCommand(ctx context.Context, backend vcs.VCS, dir string) error {
 	dir = strings.TrimSuffix(dir, "/.git")
-	cmd := exec.Command("git", "-C", dir, "pull", "--depth=1")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+
+	if isBareRepoDir(dir) {
+		return fetchCommand(ctx, backend, dir)
+	}
 
 	slog.Info("pulling dir", "dir", dir)
 
-	if err := cmd.Run(); err != nil {
+	if err := backend.Pull(ctx, dir); err != nil {
 		return fmt.Errorf("error pulling %s, %s", dir, err)
 	}
 
@@ -227,6 +465,51 @@ func runCommand(dir string) error {
 	return nil
 }
 
+// fetchGitRepos function uses a worker pool to run 'git fetch --all --prune
+// --tags' in every repo in list. It is the mode used to keep a backup tree
+// of bare/mirror repos up to date, where a plain pull has no working tree
+// to update.
+func fetchGitRepos(ctx context.Context, backend vcs.VCS, list []string) *report.Report {
+	rep := &report.Report{}
+
+	processed := make(map[string]bool)
+	var mu sync.Mutex
+
+	pool.Run(ctx, flags.jobs, list, func(ctx context.Context, dir string) {
+		mu.Lock()
+		processed[dir] = true
+		mu.Unlock()
+
+		err := fetchCommand(ctx, backend, strings.TrimSuffix(dir, "/.git"))
+		if err != nil {
+			slog.Error("error in fetching git repo for dir", "dir", dir, "error", err)
+		} else {
+			runPostPullHook(ctx, dir)
+		}
+		rep.Add(dir, err)
+	})
+
+	for _, dir := range list {
+		if !processed[dir] {
+			rep.Add(dir, fmt.Errorf("not processed: %w", ctx.Err()))
+		}
+	}
+
+	return rep
+}
+
+// fetchCommand runs 'git fetch --all --prune --tags' in dir.
+func fetchCommand(ctx context.Context, backend vcs.VCS, dir string) error {
+	slog.Info("fetching dir", "dir", dir)
+
+	if err := backend.Fetch(ctx, dir); err != nil {
+		return fmt.Errorf("error fetching %s, %s", dir, err)
+	}
+
+	slog.Info("fetched dir", "dir", dir)
+	return nil
+}
+
 // parsePath function parses the path argument and returns the path as a string
 // It also checks if the path is valid
 func parsePath(path string) string {
@@ -277,125 +560,3 @@ func saveToFile(fileName string, list []string) {
 
 	slog.Info("Saved git repository list to file", "file", fileName)
 }
-
-// getExportData function gets the data to export to JSON
-func getExportData(dirs []string) map[string]string {
-	var mtx sync.Mutex
-	var wg sync.WaitGroup
-
-	wg.Add(len(dirs))
-	prefix := parsePath(flags.path) + "/"
-	jsonData := make(map[string]string, len(dirs))
-
-	for _, dir := range dirs {
-		go func(dir string) {
-			defer wg.Done()
-			if dir == "" {
-				return
-			}
-
-			data := getGitRepo(dir)
-			dir = strings.TrimPrefix(strings.TrimSuffix(dir, "/.git"), prefix)
-
-			mtx.Lock()
-			jsonData[dir] = data
-			mtx.Unlock()
-		}(dir)
-	}
-	wg.Wait()
-	return jsonData
-}
-
-// // getGitRepo function gets the git repository from the directory
-func getGitRepo(dir string) string {
-	output, err := exec.Command("git", "-C", dir, "config", "--get", "remote.origin.url").Output()
-	if err != nil {
-		log.Fatalf("Error in getting git repo url for dir %s: %v", dir, err)
-	}
-
-	return strings.TrimSpace(string(output))
-}
-
-// saveFile function saves the data to a file
-func saveFile(filename string, data []byte) {
-	if err := os.WriteFile(filename, data, 0o644); err != nil {
-		log.Fatalf("Error in writing to file %s: %v", filename, err)
-	}
-}
-
-// exportJSON function exports the data to a JSON file
-func exportJSON(data map[string]string) {
-	result, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		log.Fatalf("Error in marshalling JSON: %v", err)
-	}
-
-	fileName := flags.fileName
-	if fileName == "" {
-		fileName = "export.json"
-	} else if !strings.HasSuffix(fileName, ".json") {
-		fileName += ".json"
-	}
-	saveFile(fileName, result)
-}
-
-// importJSON function imports the data from a JSON file
-func importJSON(filename string) map[string]string {
-	if filename == "" {
-		slog.Warn("Filename not specified. Using 'export.json' as default")
-		filename = "export.json"
-	}
-
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		log.Fatalf("Error in reading file %s: %v", filename, err)
-	}
-
-	jsonData := make(map[string]string)
-	if err = json.Unmarshal(data, &jsonData); err != nil {
-		log.Fatalf("Error in unmarshalling JSON %s: %v", filename, err)
-	}
-
-	return jsonData
-}
-
-// createRepos function creates the git repositories
-func createRepos(data map[string]string) {
-	wg := sync.WaitGroup{}
-	wg.Add(len(data))
-	importPath := parsePath(flags.path) + "/"
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
-
-	for dir, url := range data {
-		go func(dir, url string) {
-			defer wg.Done()
-			dir = importPath + dir
-			if err := os.MkdirAll(dir, 0o755); err != nil {
-				log.Fatalf("Error in creating directory %s: %v", dir, err)
-			}
-			clone(dir, url)
-		}(dir, url)
-	}
-
-	select {
-	case <-c:
-		slog.Info("Received interrupt signal, terminating...")
-		os.Exit(1)
-	default:
-		wg.Wait()
-	}
-}
-
-// clone function clones the git repository
-func clone(dir, url string) {
-	cmd := exec.Command("git", "clone", url, dir)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		log.Fatalf("Error in cloning git repo %s: %v", url, err)
-	}
-
-	slog.Info("Cloned git repo", "url", url)
-}
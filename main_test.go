@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/AbhinavAchha/syncrepo/internal/config"
+)
+
+func TestApplyConfigDefaultsPrecedence(t *testing.T) {
+	origFlags := flags
+	origCfg := cfg
+	t.Cleanup(func() {
+		flags = origFlags
+		cfg = origCfg
+	})
+
+	flags = struct {
+		path     string
+		fileName string
+		pull     bool
+		list     bool
+		help     bool
+		export   bool
+		toImport bool
+		fetch    bool
+
+		bare   bool
+		mirror bool
+		depth  int
+
+		provider        string
+		account         string
+		token           string
+		includeForks    bool
+		includePrivate  bool
+		includeArchived bool
+		nameRegexp      string
+		giteaURL        string
+
+		jobs       int
+		reportFile string
+		backend    string
+
+		config string
+	}{
+		path:    "/cli-path", // explicitly passed on the CLI
+		backend: "git",       // built-in default, not explicitly passed
+		jobs:    0,
+		depth:   0,
+	}
+
+	cfg = &config.Config{
+		Path:    "/config-path",
+		Backend: "go-git",
+		Jobs:    8,
+		Depth:   3,
+	}
+
+	// Only "path" was explicitly passed on the command line.
+	applyConfigDefaults(map[string]bool{"path": true})
+
+	if flags.path != "/cli-path" {
+		t.Errorf("path = %q, want CLI value /cli-path to win over config", flags.path)
+	}
+	if flags.backend != "go-git" {
+		t.Errorf("backend = %q, want config value go-git since it wasn't passed on the CLI", flags.backend)
+	}
+	if flags.jobs != 8 {
+		t.Errorf("jobs = %d, want config value 8", flags.jobs)
+	}
+	if flags.depth != 3 {
+		t.Errorf("depth = %d, want config value 3", flags.depth)
+	}
+}
@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const bitbucketAPI = "https://api.bitbucket.org/2.0"
+
+// BitbucketClient lists repositories in a Bitbucket workspace.
+type BitbucketClient struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewBitbucketClient returns a client authenticated with an access token,
+// sent as a Bearer token.
+func NewBitbucketClient(token string) *BitbucketClient {
+	return &BitbucketClient{token: token, httpClient: http.DefaultClient}
+}
+
+type bitbucketRepo struct {
+	Name       string    `json:"name"`
+	IsPrivate  bool      `json:"is_private"`
+	Parent     *struct{} `json:"parent"`
+	Mainbranch *struct{} `json:"mainbranch"`
+	Links      struct {
+		Clone []struct {
+			Name string `json:"name"`
+			Href string `json:"href"`
+		} `json:"clone"`
+	} `json:"links"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+type bitbucketPage struct {
+	Values []bitbucketRepo `json:"values"`
+	Next   string          `json:"next"`
+}
+
+// List enumerates every repo in the workspace. Bitbucket has no repo-level
+// "archived" concept, so filter.IncludeArchived has no effect here.
+func (c *BitbucketClient) List(ctx context.Context, account string, filter Filter) ([]Repo, error) {
+	reqURL := fmt.Sprintf("%s/repositories/%s?pagelen=100", bitbucketAPI, account)
+
+	var out []Repo
+	for reqURL != "" {
+		page, err := c.listPage(ctx, reqURL)
+		if err != nil {
+			return nil, fmt.Errorf("bitbucket: listing repos for %s: %w", account, err)
+		}
+
+		for _, r := range page.Values {
+			repo := Repo{
+				Name:     r.Name,
+				CloneURL: httpsCloneURL(r),
+				Private:  r.IsPrivate,
+				Fork:     r.Parent != nil,
+			}
+			if filter.Match(repo) {
+				out = append(out, repo)
+			}
+		}
+
+		reqURL = page.Next
+	}
+
+	return out, nil
+}
+
+func httpsCloneURL(r bitbucketRepo) string {
+	for _, l := range r.Links.Clone {
+		if l.Name == "https" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func (c *BitbucketClient) listPage(ctx context.Context, reqURL string) (*bitbucketPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s for %s", resp.Status, reqURL)
+	}
+
+	var page bitbucketPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
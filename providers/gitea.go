@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// defaultGiteaAPI is used when the account isn't hosted on a custom instance.
+// Most users self-host Gitea, so callers are expected to override BaseURL
+// (main.go does this via the -gitea-url flag).
+const defaultGiteaAPI = "https://gitea.com/api/v1"
+
+// GiteaClient lists repositories owned by a Gitea organization.
+type GiteaClient struct {
+	// BaseURL points at the Gitea instance's API root, e.g.
+	// "https://git.example.com/api/v1". Defaults to gitea.com.
+	BaseURL string
+
+	token      string
+	httpClient *http.Client
+}
+
+// NewGiteaClient returns a client authenticated with a personal access
+// token, pointed at gitea.com. Set BaseURL to target a self-hosted instance.
+func NewGiteaClient(token string) *GiteaClient {
+	return &GiteaClient{BaseURL: defaultGiteaAPI, token: token, httpClient: http.DefaultClient}
+}
+
+type giteaRepo struct {
+	Name     string   `json:"name"`
+	CloneURL string   `json:"clone_url"`
+	Private  bool     `json:"private"`
+	Fork     bool     `json:"fork"`
+	Archived bool     `json:"archived"`
+	Topics   []string `json:"topics"`
+}
+
+// List enumerates every repo owned by the org account.
+func (c *GiteaClient) List(ctx context.Context, account string, filter Filter) ([]Repo, error) {
+	var out []Repo
+
+	for page := 1; ; page++ {
+		reqURL := fmt.Sprintf("%s/orgs/%s/repos?limit=50&page=%d", c.BaseURL, account, page)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "token "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("gitea: listing repos for %s: %w", account, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("gitea: unexpected status %s for %s", resp.Status, reqURL)
+		}
+
+		var batch []giteaRepo
+		err = json.NewDecoder(resp.Body).Decode(&batch)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, r := range batch {
+			repo := Repo{
+				Name:     r.Name,
+				CloneURL: r.CloneURL,
+				Private:  r.Private,
+				Fork:     r.Fork,
+				Archived: r.Archived,
+				Topics:   r.Topics,
+			}
+			if filter.Match(repo) {
+				out = append(out, repo)
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+const githubAPI = "https://api.github.com"
+
+// GitHubClient lists repositories owned by a GitHub user or organization.
+type GitHubClient struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitHubClient returns a client authenticated with a personal access
+// token. An empty token is allowed, but only public repos will be visible.
+func NewGitHubClient(token string) *GitHubClient {
+	return &GitHubClient{token: token, httpClient: http.DefaultClient}
+}
+
+type githubRepo struct {
+	Name     string   `json:"name"`
+	CloneURL string   `json:"clone_url"`
+	Private  bool     `json:"private"`
+	Fork     bool     `json:"fork"`
+	Archived bool     `json:"archived"`
+	Topics   []string `json:"topics"`
+}
+
+// List enumerates every repo owned by account, trying the org endpoint
+// first and falling back to the user endpoint.
+func (c *GitHubClient) List(ctx context.Context, account string, filter Filter) ([]Repo, error) {
+	repos, err := c.listPages(ctx, fmt.Sprintf("%s/orgs/%s/repos", githubAPI, account))
+	if err != nil {
+		repos, err = c.listPages(ctx, fmt.Sprintf("%s/users/%s/repos", githubAPI, account))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("github: listing repos for %s: %w", account, err)
+	}
+
+	var out []Repo
+	for _, r := range repos {
+		repo := Repo{
+			Name:     r.Name,
+			CloneURL: r.CloneURL,
+			Private:  r.Private,
+			Fork:     r.Fork,
+			Archived: r.Archived,
+			Topics:   r.Topics,
+		}
+		if filter.Match(repo) {
+			out = append(out, repo)
+		}
+	}
+	return out, nil
+}
+
+func (c *GitHubClient) listPages(ctx context.Context, url string) ([]githubRepo, error) {
+	var all []githubRepo
+	url += "?per_page=100"
+
+	for url != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %s for %s", resp.Status, url)
+		}
+
+		var page []githubRepo
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		url = nextLink(resp.Header.Get("Link"))
+	}
+
+	return all, nil
+}
+
+var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink extracts the "next" URL from a GitHub-style paginated Link header.
+func nextLink(header string) string {
+	m := linkNextRe.FindStringSubmatch(header)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
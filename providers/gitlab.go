@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const gitlabAPI = "https://gitlab.com/api/v4"
+
+// GitLabClient lists the projects under a GitLab group, recursing into
+// subgroups.
+type GitLabClient struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewGitLabClient returns a client authenticated with a personal access
+// token.
+func NewGitLabClient(token string) *GitLabClient {
+	return &GitLabClient{token: token, httpClient: http.DefaultClient}
+}
+
+type gitlabProject struct {
+	Name              string    `json:"name"`
+	HTTPURLToRepo     string    `json:"http_url_to_repo"`
+	Visibility        string    `json:"visibility"`
+	ForkedFromProject *struct{} `json:"forked_from_project"`
+	Archived          bool      `json:"archived"`
+	TagList           []string  `json:"tag_list"`
+}
+
+// List enumerates every project under the group (and its subgroups, since
+// GitLab's "include_subgroups" flag is set).
+func (c *GitLabClient) List(ctx context.Context, account string, filter Filter) ([]Repo, error) {
+	reqURL := fmt.Sprintf("%s/groups/%s/projects?include_subgroups=true&per_page=100", gitlabAPI, url.PathEscape(account))
+
+	var all []gitlabProject
+	for reqURL != "" {
+		page, next, err := c.listPage(ctx, reqURL)
+		if err != nil {
+			return nil, fmt.Errorf("gitlab: listing projects for %s: %w", account, err)
+		}
+		all = append(all, page...)
+		reqURL = next
+	}
+
+	var out []Repo
+	for _, p := range all {
+		repo := Repo{
+			Name:     p.Name,
+			CloneURL: p.HTTPURLToRepo,
+			Private:  p.Visibility != "public",
+			Fork:     p.ForkedFromProject != nil,
+			Archived: p.Archived,
+			Topics:   p.TagList,
+		}
+		if filter.Match(repo) {
+			out = append(out, repo)
+		}
+	}
+	return out, nil
+}
+
+func (c *GitLabClient) listPage(ctx context.Context, reqURL string) ([]gitlabProject, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %s for %s", resp.Status, reqURL)
+	}
+
+	var page []gitlabProject
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, "", err
+	}
+
+	return page, nextLink(resp.Header.Get("Link")), nil
+}
@@ -0,0 +1,106 @@
+// Package providers implements clients for the git-forge REST APIs (GitHub,
+// GitLab, Gitea, Bitbucket) so that syncrepo can enumerate every repository
+// in an account and feed the result straight into the existing import
+// pipeline, instead of requiring a hand-maintained export.json.
+package providers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// Repo describes a single repository discovered on a remote git host.
+type Repo struct {
+	Name     string
+	CloneURL string
+	Private  bool
+	Fork     bool
+	Archived bool
+	Topics   []string
+}
+
+// Filter narrows down the repositories a Lister returns for an account.
+type Filter struct {
+	IncludeForks    bool
+	IncludePrivate  bool
+	IncludeArchived bool
+	// NameRegexp, if set, only keeps repos whose name matches.
+	NameRegexp string
+	// Topics, if set, only keeps repos that have at least one of these topics.
+	Topics []string
+}
+
+// Match reports whether repo passes the filter.
+func (f Filter) Match(repo Repo) bool {
+	if repo.Fork && !f.IncludeForks {
+		return false
+	}
+	if repo.Private && !f.IncludePrivate {
+		return false
+	}
+	if repo.Archived && !f.IncludeArchived {
+		return false
+	}
+	if f.NameRegexp != "" {
+		re, err := regexp.Compile(f.NameRegexp)
+		if err != nil || !re.MatchString(repo.Name) {
+			return false
+		}
+	}
+	if len(f.Topics) > 0 && !hasAnyTopic(repo.Topics, f.Topics) {
+		return false
+	}
+	return true
+}
+
+func hasAnyTopic(have, want []string) bool {
+	for _, w := range want {
+		for _, h := range have {
+			if h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Lister enumerates the repositories belonging to an account (a user, org,
+// group or workspace, depending on the provider) on a git host.
+type Lister interface {
+	// List returns every repo under account that passes filter.
+	List(ctx context.Context, account string, filter Filter) ([]Repo, error)
+}
+
+// New returns the Lister for the given provider name ("github", "gitlab",
+// "gitea" or "bitbucket"), authenticated with token. baseURL overrides the
+// API root for self-hosted instances; it is only meaningful for "gitea" and
+// is ignored by the other providers.
+func New(provider, token, baseURL string) (Lister, error) {
+	switch provider {
+	case "github":
+		return NewGitHubClient(token), nil
+	case "gitlab":
+		return NewGitLabClient(token), nil
+	case "gitea":
+		client := NewGiteaClient(token)
+		if baseURL != "" {
+			client.BaseURL = baseURL
+		}
+		return client, nil
+	case "bitbucket":
+		return NewBitbucketClient(token), nil
+	default:
+		return nil, fmt.Errorf("providers: unknown provider %q", provider)
+	}
+}
+
+// ToCloneMap converts a repo list to a name->clone-URL map. Repos sharing a
+// name collapse to the last one in the list.
+func ToCloneMap(repos []Repo) map[string]string {
+	m := make(map[string]string, len(repos))
+	for _, r := range repos {
+		m[r.Name] = r.CloneURL
+	}
+	return m
+}
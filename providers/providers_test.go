@@ -0,0 +1,93 @@
+package providers
+
+import "testing"
+
+func TestFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		repo   Repo
+		want   bool
+	}{
+		{
+			name:   "fork excluded by default",
+			filter: Filter{},
+			repo:   Repo{Name: "a", Fork: true},
+			want:   false,
+		},
+		{
+			name:   "fork included when requested",
+			filter: Filter{IncludeForks: true},
+			repo:   Repo{Name: "a", Fork: true},
+			want:   true,
+		},
+		{
+			name:   "private excluded by default",
+			filter: Filter{},
+			repo:   Repo{Name: "a", Private: true},
+			want:   false,
+		},
+		{
+			name:   "archived excluded by default",
+			filter: Filter{},
+			repo:   Repo{Name: "a", Archived: true},
+			want:   false,
+		},
+		{
+			name:   "name regexp mismatch",
+			filter: Filter{NameRegexp: "^svc-"},
+			repo:   Repo{Name: "frontend"},
+			want:   false,
+		},
+		{
+			name:   "name regexp match",
+			filter: Filter{NameRegexp: "^svc-"},
+			repo:   Repo{Name: "svc-billing"},
+			want:   true,
+		},
+		{
+			name:   "invalid regexp never matches",
+			filter: Filter{NameRegexp: "("},
+			repo:   Repo{Name: "svc-billing"},
+			want:   false,
+		},
+		{
+			name:   "topic required but missing",
+			filter: Filter{Topics: []string{"infra"}},
+			repo:   Repo{Name: "a", Topics: []string{"web"}},
+			want:   false,
+		},
+		{
+			name:   "topic present",
+			filter: Filter{Topics: []string{"infra", "web"}},
+			repo:   Repo{Name: "a", Topics: []string{"web"}},
+			want:   true,
+		},
+		{
+			name:   "plain repo passes default filter",
+			filter: Filter{},
+			repo:   Repo{Name: "a"},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.repo); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToCloneMap(t *testing.T) {
+	repos := []Repo{
+		{Name: "a", CloneURL: "https://example.com/a.git"},
+		{Name: "b", CloneURL: "https://example.com/b.git"},
+	}
+
+	m := ToCloneMap(repos)
+	if len(m) != 2 || m["a"] != repos[0].CloneURL || m["b"] != repos[1].CloneURL {
+		t.Fatalf("got %v, want clone map matching %v", m, repos)
+	}
+}
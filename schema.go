@@ -0,0 +1,35 @@
+package main
+
+// exportVersion is the current version of the JSON schema written by
+// exportJSON and understood by importJSON. Bump it whenever ExportRepo gains
+// or changes fields in an incompatible way.
+const exportVersion = 1
+
+// ExportRepo captures everything needed to faithfully restore a single git
+// repository: its remotes, the branch and commit it was checked out at, and
+// any submodules or git-lfs usage.
+type ExportRepo struct {
+	Path string `json:"path"`
+	// Remotes maps remote name (e.g. "origin", "upstream") to its URL.
+	Remotes map[string]string `json:"remotes"`
+	// DefaultBranch is the branch that was checked out when exported.
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// Head is the sha of the commit that was checked out when exported.
+	Head string `json:"head,omitempty"`
+	// Submodules lists the .gitmodules entries found in the repo.
+	Submodules []Submodule `json:"submodules,omitempty"`
+	// LFS is true if the repo uses git-lfs (a .gitattributes filter=lfs rule).
+	LFS bool `json:"lfs,omitempty"`
+}
+
+// Submodule is a single entry parsed out of a repo's .gitmodules file.
+type Submodule struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// ExportData is the versioned export format written to export.json.
+type ExportData struct {
+	Version int          `json:"version"`
+	Repos   []ExportRepo `json:"repos"`
+}